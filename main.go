@@ -2,29 +2,102 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/alecthomas/kong"
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/earthboundkid/versioninfo/v2"
+	"github.com/pmezard/go-difflib/difflib"
 	"gopkg.in/yaml.v3"
 )
 
 // CLI represents the command-line interface structure
 type CLI struct {
-	InputFile string           `name:"file" short:"f" help:"Input YAML file path" type:"existingfile" required:""`
-	YamlPaths []string         `name:"yaml-path" short:"p" help:"YAML path(s) in dot notation. Bracket selectors [*] and [N] can appear at the end or mid-path to loop over sequences or mappings with [*], or index sequences with [N] (e.g., 'items[*].meta', 'servers[0].roles'). At the target: mappings have keys sorted; sequences are sorted by the first field of each element. Repeat -p to process multiple paths in order." required:""`
-	Write     bool             `name:"write" short:"w" help:"Write changes back to the input file instead of printing to stdout"`
-	SortType  string           `name:"sort" short:"t" help:"Sort type for mapping keys: 'alphanumeric' (default) or 'human' (common keys first, then the rest alphanumeric)" enum:"alphanumeric,human" default:"alphanumeric"`
-	Verbose   bool             `name:"verbose" short:"v" help:"Verbose output"`
-	Version   kong.VersionFlag `name:"version" short:"V" help:"Print version information and exit" version:"${version}"`
+	InputFiles   []string         `name:"file" short:"f" sep:"none" help:"Input YAML file path, or a glob pattern (e.g. 'manifests/**/*.yaml') expanding to multiple files. Repeat -f to process several paths/patterns, in order. Omit -f, or pass '-', to read a single document stream from stdin and write to stdout."`
+	YamlPaths    []string         `name:"yaml-path" short:"p" sep:"none" help:"YAML path(s) in dot notation. A leading '#N.' or '#*.' restricts the path to document index N or to every document of a multi-document stream respectively; a path with no '#' prefix also applies to every document. Bracket selectors [*] and [N] can appear at the end or mid-path to loop over sequences or mappings with [*], or index sequences with [N] (e.g., 'items[*].meta', 'servers[0].roles'). At the target: mappings have keys sorted; sequences of mappings are sorted by the first field of each element, or by an explicit key with '[*by=<subpath>]' (e.g. '.containers[*by=name]', '.rules[*by=metadata.name]'), optionally with ',desc' to reverse and/or ',numeric' to compare as numbers. Repeat -p to process multiple paths in order." required:""`
+	Write        bool             `name:"write" short:"w" help:"Write changes back to the input file instead of printing to stdout"`
+	SortType     string           `name:"sort" short:"t" help:"Sort type for mapping keys: 'alphanumeric' (default), 'human' (built-in preset: common keys first, then the rest alphanumeric), or the name of a preset defined via --config" default:"alphanumeric"`
+	Config       string           `name:"config" short:"c" help:"YAML file defining named sort presets under a top-level 'presets:' map (each preset has 'keys:', and optionally 'patterns:' and 'paths:'); presets here override built-ins of the same name, including 'human'" type:"existingfile"`
+	Check        bool             `name:"check" help:"Check whether the file is already sorted; exit 0 if so, exit 1 otherwise, without writing anything. Pair with --diff to see what would change. Note: -c is taken by --config, so --check has no short flag."`
+	Diff         bool             `name:"diff" short:"d" help:"With --check, print a unified diff of the changes that would be made to stderr"`
+	OutputFormat string           `name:"output" short:"o" help:"Output format after sorting: 'yaml' (default), 'json' (one JSON value; multiple documents are wrapped in an array), or 'ndjson' (one compact JSON object per line, one per document)" enum:"yaml,json,ndjson" default:"yaml"`
+	Verbose      bool             `name:"verbose" short:"v" help:"Verbose output"`
+	Version      kong.VersionFlag `name:"version" short:"V" help:"Print version information and exit" version:"${version}"`
+
+	presets map[string]SortPreset
 }
 
 const description = `Yet Another Yaml Sorter`
 
+// SortPreset describes one named key ordering that --sort can select.
+type SortPreset struct {
+	// Keys are ranked first, in the order listed; keys not listed fall through
+	// to Patterns, then to alphanumeric order.
+	Keys []string `yaml:"keys"`
+	// Patterns rank below Keys, in the order listed. Each entry is either a
+	// glob (where '*' matches any run of characters) or, when wrapped in
+	// slashes (e.g. "/^x-.*/"), a regular expression.
+	Patterns []string `yaml:"patterns"`
+	// Paths, if set, restricts this preset to YAML paths (the -p argument)
+	// matching one of these glob/regex patterns; outside them the preset has
+	// no effect and keys fall back to alphanumeric order.
+	Paths []string `yaml:"paths"`
+}
+
+// sortConfig is the top-level shape of the --config file.
+type sortConfig struct {
+	Presets map[string]SortPreset `yaml:"presets"`
+}
+
+const humanPresetName = "human"
+
+// builtinPresets returns the presets available with no --config file.
+func builtinPresets() map[string]SortPreset {
+	return map[string]SortPreset{
+		humanPresetName: {Keys: []string{"apiVersion", "kind", "metadata", "name", "namespace", "labels", "annotations", "id", "version"}},
+	}
+}
+
+// loadPresets reads cli.Config, if set, and merges its presets over the
+// built-ins, so a config file can redefine "human" or add new preset names.
+func (cli *CLI) loadPresets() error {
+	presets := builtinPresets()
+	if cli.Config != "" {
+		data, err := os.ReadFile(cli.Config)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %v", err)
+		}
+		var cfg sortConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("failed to parse config file: %v", err)
+		}
+		for name, preset := range cfg.Presets {
+			presets[name] = preset
+		}
+	}
+	cli.presets = presets
+	return nil
+}
+
+// presetFor looks up name, falling back to the built-ins if loadPresets was
+// never called (e.g. in tests that construct a CLI directly).
+func (cli CLI) presetFor(name string) (SortPreset, bool) {
+	presets := cli.presets
+	if presets == nil {
+		presets = builtinPresets()
+	}
+	preset, ok := presets[name]
+	return preset, ok
+}
+
 func main() {
 	var cli CLI
 	_ = kong.Parse(&cli,
@@ -35,131 +108,503 @@ func main() {
 		kong.ConfigureHelp(kong.HelpOptions{Compact: true}),
 	)
 
-	// Read
-	doc, indent, err := cli.ReadFile()
-	if err != nil {
+	if err := cli.loadPresets(); err != nil {
 		fail(err)
 	}
 
-	// Perform Sort
-	if err := cli.SortYaml(doc); err != nil {
+	sources, err := resolveSources(cli.InputFiles)
+	if err != nil {
 		fail(err)
 	}
 
-	// Print sorted yaml
-	if cli.Verbose || !cli.Write {
-		if err := PrintYaml(doc, indent); err != nil {
+	anyDiff := false
+	for _, src := range sources {
+		diff, err := cli.processSource(src)
+		if err != nil {
 			fail(err)
 		}
+		anyDiff = anyDiff || diff
 	}
 
-	// Save sorted yaml
-	if cli.Write {
-		if err := cli.WriteYaml(doc, indent); err != nil {
-			fail(err)
+	if cli.Check && anyDiff {
+		os.Exit(1)
+	}
+}
+
+// source is a single input: either a file path or stdin (when name is "-" or
+// empty, e.g. no -f flag was given at all).
+type source struct {
+	name string
+}
+
+func (s source) isStdin() bool {
+	return s.name == "" || s.name == "-"
+}
+
+// displayName is used in error messages and unified diff headers.
+func (s source) displayName() string {
+	if s.isStdin() {
+		return "<stdin>"
+	}
+	return s.name
+}
+
+func (s source) readAll() ([]byte, error) {
+	if s.isStdin() {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(s.name)
+}
+
+// resolveSources expands patterns (file paths, glob patterns like
+// 'manifests/**/*.yaml', or '-' for stdin) into a flat, ordered list of
+// sources. With no patterns at all, it reads a single document stream from
+// stdin, matching the pre-multi-file behavior of piping into yays.
+func resolveSources(patterns []string) ([]source, error) {
+	if len(patterns) == 0 {
+		return []source{{name: "-"}}, nil
+	}
+	var sources []source
+	for _, pattern := range patterns {
+		if pattern == "-" {
+			sources = append(sources, source{name: "-"})
+			continue
+		}
+		matches, err := doublestar.FilepathGlob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			if _, err := os.Stat(pattern); err != nil {
+				return nil, fmt.Errorf("no files match %q", pattern)
+			}
+			matches = []string{pattern}
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			sources = append(sources, source{name: m})
 		}
 	}
+	return sources, nil
 }
 
-func PrintYaml(doc *yaml.Node, indent int) error {
-	enc := yaml.NewEncoder(os.Stdout)
-	enc.SetIndent(indent)
-	err := enc.Encode(doc)
-	_ = enc.Close()
+// processSource reads, sorts, and (depending on flags) checks, prints, or
+// writes back a single source. It returns true if --check found the source
+// not already sorted.
+func (cli CLI) processSource(src source) (bool, error) {
+	data, err := src.readAll()
 	if err != nil {
-		return fmt.Errorf("failed to encode YAML: %v", err)
+		return false, fmt.Errorf("failed to read %s: %v", src.displayName(), err)
 	}
-	return nil
+
+	docs, indent, err := decodeYAMLDocuments(data)
+	if err != nil {
+		return false, fmt.Errorf("%s: %v", src.displayName(), err)
+	}
+
+	if err := cli.SortYaml(docs); err != nil {
+		return false, fmt.Errorf("%s: %v", src.displayName(), err)
+	}
+
+	if cli.Check {
+		// --check always compares against the re-encoded YAML, regardless of
+		// --output: the source bytes are YAML, so a JSON/NDJSON re-encoding
+		// could never match them even when the file is already sorted.
+		sortedYaml, err := encodeYaml(docs, indent)
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(data, sortedYaml) {
+			return false, nil
+		}
+		if cli.Diff {
+			diff, err := unifiedDiff(src.displayName(), data, sortedYaml)
+			if err != nil {
+				return false, err
+			}
+			_, _ = fmt.Fprint(os.Stderr, diff)
+		}
+		return true, nil
+	}
+
+	output, err := cli.encodeDocs(docs, indent)
+	if err != nil {
+		return false, err
+	}
+
+	// A file written in place doesn't also need printing, unless -v was given;
+	// stdin has nowhere else to go, so it always prints.
+	writeToFile := cli.Write && !src.isStdin()
+	if cli.Verbose || !writeToFile {
+		if _, err := os.Stdout.Write(output); err != nil {
+			return false, err
+		}
+	}
+	if writeToFile {
+		if err := writeBytesToFile(src.name, output); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// decodeYAMLDocuments decodes every '---'-separated document in data and
+// detects its indentation, so multi-document streams are no longer truncated
+// to their first document.
+func decodeYAMLDocuments(data []byte) ([]*yaml.Node, int, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, 0, fmt.Errorf("failed to decode YAML: %v", err)
+		}
+		docs = append(docs, &doc)
+	}
+	if len(docs) == 0 {
+		return nil, 0, fmt.Errorf("no YAML documents found")
+	}
+	return docs, detectIndentation(data), nil
+}
+
+// unifiedDiff renders a unified diff between before and after, as if filename
+// were rewritten in place.
+func unifiedDiff(filename string, before, after []byte) (string, error) {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(before)),
+		B:        difflib.SplitLines(string(after)),
+		FromFile: filename,
+		ToFile:   filename,
+		Context:  3,
+	}
+	return difflib.GetUnifiedDiffString(diff)
 }
 
-func (cli CLI) WriteYaml(doc *yaml.Node, indent int) error {
+// encodeDocs renders docs in cli.OutputFormat: 'yaml' (default, or empty)
+// re-emits them as YAML; 'json' and 'ndjson' convert each document to JSON
+// (see yamlNodeToJSON).
+func (cli CLI) encodeDocs(docs []*yaml.Node, indent int) ([]byte, error) {
+	switch cli.OutputFormat {
+	case "", "yaml":
+		return encodeYaml(docs, indent)
+	case "json":
+		return encodeJSON(docs, false)
+	case "ndjson":
+		return encodeJSON(docs, true)
+	default:
+		return nil, fmt.Errorf("unknown output format %q", cli.OutputFormat)
+	}
+}
+
+// encodeYaml encodes docs with yaml.Encoder, which writes the '---' document
+// separator automatically between successive Encode calls.
+func encodeYaml(docs []*yaml.Node, indent int) ([]byte, error) {
 	var buf bytes.Buffer
 	enc := yaml.NewEncoder(&buf)
 	enc.SetIndent(indent)
-	err := enc.Encode(doc)
-	_ = enc.Close()
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			_ = enc.Close()
+			return nil, fmt.Errorf("failed to encode YAML: %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeJSON converts each document to JSON via yamlNodeToJSON. With ndjson,
+// every document is written compactly on its own line. Otherwise, a single
+// document is pretty-printed as one JSON value; multiple documents are
+// wrapped in a JSON array so the output is always one well-formed value.
+func encodeJSON(docs []*yaml.Node, ndjson bool) ([]byte, error) {
+	values := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		values[i] = yamlNodeToJSON(doc)
+	}
+	if ndjson {
+		var buf bytes.Buffer
+		for _, v := range values {
+			line, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode JSON: %v", err)
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), nil
+	}
+	var out interface{} = values
+	if len(values) == 1 {
+		out = values[0]
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to encode YAML: %v", err)
+		return nil, fmt.Errorf("failed to encode JSON: %v", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// yamlNodeToJSON converts a *yaml.Node into plain values json.Marshal
+// understands: mappings become orderedMaps (preserving their sorted key
+// order), sequences become []interface{}, and scalars are decoded per their
+// YAML tag (see scalarToJSON).
+func yamlNodeToJSON(n *yaml.Node) interface{} {
+	if n == nil {
+		return nil
+	}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil
+		}
+		return yamlNodeToJSON(n.Content[0])
+	case yaml.MappingNode:
+		m := orderedMap{keys: make([]string, 0, len(n.Content)/2), values: make([]interface{}, 0, len(n.Content)/2)}
+		for i := 0; i < len(n.Content); i += 2 {
+			m.keys = append(m.keys, n.Content[i].Value)
+			m.values = append(m.values, yamlNodeToJSON(n.Content[i+1]))
+		}
+		return m
+	case yaml.SequenceNode:
+		arr := make([]interface{}, len(n.Content))
+		for i, el := range n.Content {
+			arr[i] = yamlNodeToJSON(el)
+		}
+		return arr
+	case yaml.ScalarNode:
+		return scalarToJSON(n)
+	default:
+		return nil
 	}
-	info, err := os.Stat(cli.InputFile)
+}
+
+// scalarToJSON decodes a YAML scalar's value according to its tag: !!int and
+// !!float become a number, !!bool a bool, !!null nil; !!str and any other tag
+// are passed through as a string.
+func scalarToJSON(n *yaml.Node) interface{} {
+	switch n.Tag {
+	case "!!int":
+		if i, err := strconv.ParseInt(n.Value, 10, 64); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(n.Value, 64); err == nil {
+			return f
+		}
+		return n.Value
+	case "!!float":
+		if f, err := strconv.ParseFloat(n.Value, 64); err == nil {
+			return f
+		}
+		return n.Value
+	case "!!bool":
+		if b, err := strconv.ParseBool(n.Value); err == nil {
+			return b
+		}
+		return n.Value
+	case "!!null":
+		return nil
+	default:
+		return n.Value
+	}
+}
+
+// orderedMap is a JSON object that marshals its fields in insertion order,
+// rather than the alphabetical order encoding/json imposes on map[string]any.
+type orderedMap struct {
+	keys   []string
+	values []interface{}
+}
+
+func (m orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(m.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// writeBytesToFile writes data back to path, preserving its existing file
+// permissions.
+func writeBytesToFile(path string, data []byte) error {
+	info, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("failed to stat input file: %v", err)
 	}
 	perm := info.Mode().Perm()
-	if err := os.WriteFile(cli.InputFile, buf.Bytes(), perm); err != nil {
+	if err := os.WriteFile(path, data, perm); err != nil {
 		return fmt.Errorf("failed to write back to file: %v", err)
 	}
 	return nil
 }
 
-func (cli CLI) rankSortType(key string) int {
-	humanCommonOrder := []string{"apiVersion", "kind", "metadata", "name", "namespace", "labels", "annotations", "id", "version"}
-	switch cli.SortType {
-	case "alphanumeric":
-		return len(humanCommonOrder) + 1
-	case "human":
-		for i, k := range humanCommonOrder {
-			if key == k {
-				return i
-			}
-		}
-		return len(humanCommonOrder) + 1
-	default:
+// rankSortType ranks key under the preset named by cli.SortType for a mapping
+// found at the given YAML path (the -p argument). Keys with an equal rank
+// fall back to alphanumeric order in sortMappingNodeKeys.
+func (cli CLI) rankSortType(key, path string) int {
+	if cli.SortType == "" || cli.SortType == "alphanumeric" {
+		return 0
+	}
+	preset, ok := cli.presetFor(cli.SortType)
+	if !ok {
 		// Fallback to alphanumeric behavior for unknown sort types
-		return len(humanCommonOrder) + 1
+		return 0
+	}
+	if len(preset.Paths) > 0 && !matchesAny(preset.Paths, path) {
+		return 0
+	}
+	for i, k := range preset.Keys {
+		if key == k {
+			return i
+		}
+	}
+	for i, pat := range preset.Patterns {
+		if matchKeyPattern(pat, key) {
+			return len(preset.Keys) + i
+		}
+	}
+	return len(preset.Keys) + len(preset.Patterns) + 1
+}
+
+// matchesAny reports whether s matches any of patterns (see matchKeyPattern).
+func matchesAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if matchKeyPattern(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchKeyPattern matches s against pattern, which is either a glob ('*'
+// matches any run of characters) or, wrapped in slashes, a regular
+// expression. Plain globbing is used (rather than e.g. path.Match) so
+// patterns can safely contain YAML path syntax like '[*]' without it being
+// interpreted as a character class.
+func matchKeyPattern(pattern, s string) bool {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return false
+		}
+		return re.MatchString(s)
+	}
+	var b strings.Builder
+	b.WriteByte('^')
+	for _, r := range pattern {
+		if r == '*' {
+			b.WriteString(".*")
+		} else {
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteByte('$')
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return false
 	}
+	return re.MatchString(s)
 }
 
-func (cli CLI) SortYaml(doc *yaml.Node) error {
-	// Navigate and sort for each provided path, in order
+// SortYaml navigates and sorts each provided path, in order, against every
+// document in docs. A path with no '#N'/'#*' prefix applies to every
+// document, matching the single-document behavior of earlier versions.
+func (cli CLI) SortYaml(docs []*yaml.Node) error {
 	for _, path := range cli.YamlPaths {
-		steps, err := parsePathSteps(path)
+		sel, rest, err := parseDocSelector(path)
 		if err != nil {
 			return fmt.Errorf("invalid path %q: %v", path, err)
 		}
-		targets, err := resolveTargets(doc, steps)
+		steps, err := parsePathSteps(rest)
 		if err != nil {
-			return fmt.Errorf("failed to navigate to path %q: %v", path, err)
+			return fmt.Errorf("invalid path %q: %v", path, err)
+		}
+		if sel.hasIndex && (sel.index < 0 || sel.index >= len(docs)) {
+			return fmt.Errorf("document index %d out of range [0,%d) for path %q", sel.index, len(docs), path)
 		}
 
 		looping := stepsContainLoop(steps)
-		for _, target := range targets {
-			switch target.Kind {
-			case yaml.MappingNode:
-				cli.sortMappingNodeKeys(target)
-			case yaml.SequenceNode:
-				// Sort the sequence by the first field
-				sortSequenceByFirstField(target)
-			default:
-				if !looping { // preserve previous behavior: error only when not looping
-					return fmt.Errorf("target at path %q must be a mapping or sequence (got kind=%d)", path, target.Kind)
+		var spec *sortSpec
+		if len(steps) > 0 {
+			spec = steps[len(steps)-1].sortSpec
+		}
+		for docIdx, doc := range docs {
+			if sel.hasIndex && sel.index != docIdx {
+				continue
+			}
+			targets, err := resolveTargets(doc, steps)
+			if err != nil {
+				return fmt.Errorf("failed to navigate to path %q in document %d: %v", path, docIdx, err)
+			}
+			for _, target := range targets {
+				switch target.Kind {
+				case yaml.MappingNode:
+					cli.sortMappingNodeKeys(target, rest)
+				case yaml.SequenceNode:
+					// Sort the sequence by the first field, or by an explicit
+					// sort-key selector (e.g. '[*by=name]') when present.
+					sortSequenceByFirstField(target, spec)
+				default:
+					if !looping { // preserve previous behavior: error only when not looping
+						return fmt.Errorf("target at path %q must be a mapping or sequence (got kind=%d)", path, target.Kind)
+					}
+					// when looping, silently skip non-sortable scalars
 				}
-				// when looping, silently skip non-sortable scalars
 			}
 		}
 	}
 	return nil
 }
 
-func (cli CLI) ReadFile() (*yaml.Node, int, error) {
-	f, err := os.Open(cli.InputFile)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to open file: %v", err)
-	}
-	defer f.Close()
-
-	dec := yaml.NewDecoder(f)
-	var root yaml.Node
-	if err := dec.Decode(&root); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode YAML: %v", err)
-	}
+// docSelector restricts a path to a single document index of a multi-document
+// stream. A zero-value docSelector (hasIndex false) matches every document.
+type docSelector struct {
+	hasIndex bool
+	index    int
+}
 
-	// Detect indentation from original file contents
-	data, err := os.ReadFile(cli.InputFile)
+// parseDocSelector strips a leading '#N.' or '#*.' document selector from
+// path, returning the selector and the remaining path to pass to
+// parsePathSteps. A path without a '#' prefix matches every document.
+func parseDocSelector(path string) (docSelector, string, error) {
+	if !strings.HasPrefix(path, "#") {
+		return docSelector{}, path, nil
+	}
+	rest := path[1:]
+	end := strings.IndexByte(rest, '.')
+	token := rest
+	remainder := ""
+	if end != -1 {
+		token = rest[:end]
+		remainder = rest[end+1:]
+	}
+	if token == "*" {
+		return docSelector{}, remainder, nil
+	}
+	n, err := strconv.Atoi(token)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to read input file: %v", err)
+		return docSelector{}, "", fmt.Errorf("invalid document selector %q", token)
 	}
-	return &root, detectIndentation(data), nil
+	return docSelector{hasIndex: true, index: n}, remainder, nil
 }
 
 type pathStepKind int
@@ -174,6 +619,70 @@ type pathStep struct {
 	kind  pathStepKind
 	key   string
 	index int
+	// sortSpec, when set on the last step of a path, overrides how a
+	// sequence resolved by that path is ordered; see parseSortSpec.
+	sortSpec *sortSpec
+}
+
+// sortSpec pins the sort key used for a sequence of mappings, set via a
+// '[*by=<subpath>]' bracket selector (e.g. '.containers[*by=name]'). Absent,
+// sortSequenceByFirstField falls back to the first field of each element.
+type sortSpec struct {
+	by      string // dotted sub-path evaluated against each element
+	desc    bool   // reverse order
+	numeric bool   // compare the extracted value as a number
+}
+
+// parseSortSpec parses the inside of a '[...]' bracket as a sort-key
+// selector, e.g. "*by=name", "*by=name,desc", or "*by=priority,numeric". It
+// returns nil, nil if inside is not a sort-key selector (e.g. plain "*" or a
+// numeric index), so callers can fall through to their existing handling.
+func parseSortSpec(inside string) (*sortSpec, error) {
+	if !strings.HasPrefix(inside, "*by=") {
+		return nil, nil
+	}
+	parts := strings.Split(inside, ",")
+	spec := &sortSpec{by: strings.TrimPrefix(parts[0], "*by=")}
+	if spec.by == "" {
+		return nil, fmt.Errorf("sort-key selector %q is missing a key path after 'by='", inside)
+	}
+	for _, mod := range parts[1:] {
+		switch strings.TrimSpace(mod) {
+		case "desc":
+			spec.desc = true
+		case "numeric":
+			spec.numeric = true
+		default:
+			return nil, fmt.Errorf("unknown sort-key modifier %q in %q", mod, inside)
+		}
+	}
+	return spec, nil
+}
+
+// splitPathTokens splits p on '.' like strings.Split, except dots inside a
+// '[...]' bracket (e.g. the sub-path in '[*by=metadata.name]') are kept
+// intact rather than treated as path separators.
+func splitPathTokens(p string) []string {
+	var tokens []string
+	depth := 0
+	start := 0
+	for i, r := range p {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case '.':
+			if depth == 0 {
+				tokens = append(tokens, p[start:i])
+				start = i + 1
+			}
+		}
+	}
+	tokens = append(tokens, p[start:])
+	return tokens
 }
 
 func parsePathSteps(path string) ([]pathStep, error) {
@@ -181,14 +690,14 @@ func parsePathSteps(path string) ([]pathStep, error) {
 	if p == "" || p == "." {
 		return nil, nil
 	}
-	parts := strings.Split(p, ".")
+	parts := splitPathTokens(p)
 	var steps []pathStep
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
 		if part == "" { // skip empty tokens from leading/trailing dots
 			continue
 		}
-		// Bracket-only token: [*] or [0]
+		// Bracket-only token: [*], [0], or a sort-key selector like [*by=name]
 		if strings.HasPrefix(part, "[") && strings.HasSuffix(part, "]") {
 			inside := strings.TrimSpace(part[1 : len(part)-1])
 			if inside == "*" {
@@ -199,9 +708,18 @@ func parsePathSteps(path string) ([]pathStep, error) {
 				steps = append(steps, pathStep{kind: stepIndex, index: n})
 				continue
 			}
+			if spec, err := parseSortSpec(inside); err != nil {
+				return nil, err
+			} else if spec != nil {
+				if len(steps) == 0 {
+					return nil, fmt.Errorf("sort-key selector %q requires a preceding path segment", part)
+				}
+				steps[len(steps)-1].sortSpec = spec
+				continue
+			}
 			return nil, fmt.Errorf("invalid bracket selection %q", inside)
 		}
-		// Token with suffix brackets: name[*] or name[0]
+		// Token with suffix brackets: name[*], name[0], or name[*by=key]
 		if lb := strings.Index(part, "["); lb != -1 && strings.HasSuffix(part, "]") {
 			name := part[:lb]
 			if name != "" {
@@ -216,6 +734,15 @@ func parsePathSteps(path string) ([]pathStep, error) {
 				steps = append(steps, pathStep{kind: stepIndex, index: n})
 				continue
 			}
+			if spec, err := parseSortSpec(inside); err != nil {
+				return nil, err
+			} else if spec != nil {
+				if len(steps) == 0 {
+					return nil, fmt.Errorf("sort-key selector %q requires a preceding path segment", part)
+				}
+				steps[len(steps)-1].sortSpec = spec
+				continue
+			}
 			return nil, fmt.Errorf("invalid bracket selection %q", inside)
 		}
 		steps = append(steps, pathStep{kind: stepKey, key: part})
@@ -292,7 +819,7 @@ func resolveTargets(root *yaml.Node, steps []pathStep) ([]*yaml.Node, error) {
 	return cur, nil
 }
 
-func (cli CLI) sortMappingNodeKeys(n *yaml.Node) {
+func (cli CLI) sortMappingNodeKeys(n *yaml.Node, path string) {
 	if n.Kind != yaml.MappingNode {
 		return
 	}
@@ -306,7 +833,7 @@ func (cli CLI) sortMappingNodeKeys(n *yaml.Node) {
 	}
 
 	sort.SliceStable(pairs, func(i, j int) bool {
-		ri, rj := cli.rankSortType(pairs[i].k.Value), cli.rankSortType(pairs[j].k.Value)
+		ri, rj := cli.rankSortType(pairs[i].k.Value, path), cli.rankSortType(pairs[j].k.Value, path)
 		if ri != rj {
 			return ri < rj
 		}
@@ -322,8 +849,10 @@ func (cli CLI) sortMappingNodeKeys(n *yaml.Node) {
 
 // sortSequenceByFirstField sorts a sequence's items by the value of the first field
 // within each item if the item is a mapping. For non-mapping items, it falls back
-// to a comparable string for the entire item.
-func sortSequenceByFirstField(n *yaml.Node) {
+// to a comparable string for the entire item. When spec is non-nil, the items are
+// instead ordered by the value at spec.by within each element (see sortSpecValue),
+// optionally as a number (spec.numeric) and/or reversed (spec.desc).
+func sortSequenceByFirstField(n *yaml.Node, spec *sortSpec) {
 	if n.Kind != yaml.SequenceNode {
 		return
 	}
@@ -333,19 +862,56 @@ func sortSequenceByFirstField(n *yaml.Node) {
 	}
 	items := make([]item, len(n.Content))
 	for i, el := range n.Content {
-		items[i] = item{
-			key:  firstFieldComparableValue(el),
-			node: el,
+		key := firstFieldComparableValue(el)
+		if spec != nil {
+			key = sortSpecValue(el, spec.by)
+		}
+		items[i] = item{key: key, node: el}
+	}
+	less := func(i, j int) bool {
+		if spec != nil && spec.numeric {
+			vi, _ := strconv.ParseFloat(items[i].key, 64)
+			vj, _ := strconv.ParseFloat(items[j].key, 64)
+			if spec.desc {
+				return vi > vj
+			}
+			return vi < vj
+		}
+		if spec != nil && spec.desc {
+			return items[i].key > items[j].key
 		}
-	}
-	sort.SliceStable(items, func(i, j int) bool {
 		return items[i].key < items[j].key
-	})
+	}
+	sort.SliceStable(items, less)
 	for i := range items {
 		n.Content[i] = items[i].node
 	}
 }
 
+// sortSpecValue resolves a dotted sub-path (e.g. "metadata.name") against a
+// mapping element and returns its comparable string, or "" if any segment is
+// missing or the element isn't a mapping.
+func sortSpecValue(el *yaml.Node, by string) string {
+	cur := el
+	for _, part := range strings.Split(by, ".") {
+		if cur == nil || cur.Kind != yaml.MappingNode {
+			return ""
+		}
+		found := false
+		for i := 0; i < len(cur.Content); i += 2 {
+			if cur.Content[i].Value == part {
+				cur = cur.Content[i+1]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ""
+		}
+	}
+	return nodeComparableString(cur)
+}
+
 // firstFieldComparableValue returns the comparable string of the first field's value
 // if the element is a mapping; otherwise, it returns a comparable string for the element.
 func firstFieldComparableValue(el *yaml.Node) string {
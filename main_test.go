@@ -2,9 +2,13 @@ package main
 
 import (
 	"bytes"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/alecthomas/kong"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 )
@@ -199,7 +203,7 @@ is_student: false
 			root := decodeYAML(t, trim(tt.content))
 			cli := CLI{YamlPaths: tt.paths, SortType: tt.sortType}
 
-			err := cli.SortYaml(root)
+			err := cli.SortYaml([]*yaml.Node{root})
 			assert.NoError(t, err)
 
 			output := encodeYAML(t, root)
@@ -209,6 +213,423 @@ is_student: false
 	}
 }
 
+func TestCLI_SortYaml_MultiDocument(t *testing.T) {
+	tests := map[string]struct {
+		content  string
+		paths    []string
+		expected string
+	}{
+		"NoPrefix_SortsEveryDocument": {
+			paths: []string{"."},
+			content: `
+name: John
+age: 30
+---
+name: Jane
+age: 25
+`,
+			expected: `
+age: 30
+name: John
+---
+age: 25
+name: Jane
+`,
+		},
+		"DocIndexPrefix_SortsOnlyThatDocument": {
+			paths: []string{"#1."},
+			content: `
+name: John
+age: 30
+---
+name: Jane
+age: 25
+`,
+			expected: `
+name: John
+age: 30
+---
+age: 25
+name: Jane
+`,
+		},
+		"DocWildcardPrefix_SortsEveryDocument": {
+			paths: []string{"#*."},
+			content: `
+name: John
+age: 30
+---
+name: Jane
+age: 25
+`,
+			expected: `
+age: 30
+name: John
+---
+age: 25
+name: Jane
+`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			trim := func(s string) string { return strings.TrimPrefix(s, "\n") }
+			docs := decodeYAMLDocsForTest(t, trim(tt.content))
+			cli := CLI{YamlPaths: tt.paths, SortType: "alphanumeric"}
+
+			err := cli.SortYaml(docs)
+			assert.NoError(t, err)
+
+			var buf bytes.Buffer
+			enc := yaml.NewEncoder(&buf)
+			enc.SetIndent(2)
+			for _, doc := range docs {
+				assert.NoError(t, enc.Encode(doc))
+			}
+			assert.NoError(t, enc.Close())
+
+			assert.Equal(t, trim(tt.expected), buf.String())
+		})
+	}
+}
+
+func TestResolveSources(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("a: 1\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.yaml"), []byte("b: 1\n"), 0o644))
+
+	t.Run("NoPatterns_ReadsStdin", func(t *testing.T) {
+		sources, err := resolveSources(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []source{{name: "-"}}, sources)
+		assert.True(t, sources[0].isStdin())
+	})
+
+	t.Run("DashLiteral_ReadsStdin", func(t *testing.T) {
+		sources, err := resolveSources([]string{"-"})
+		assert.NoError(t, err)
+		assert.Equal(t, []source{{name: "-"}}, sources)
+	})
+
+	t.Run("PlainPath_IsUsedLiterally", func(t *testing.T) {
+		sources, err := resolveSources([]string{filepath.Join(dir, "a.yaml")})
+		assert.NoError(t, err)
+		assert.Equal(t, []source{{name: filepath.Join(dir, "a.yaml")}}, sources)
+	})
+
+	t.Run("RecursiveGlob_ExpandsToAllMatches", func(t *testing.T) {
+		sources, err := resolveSources([]string{filepath.Join(dir, "**/*.yaml")})
+		assert.NoError(t, err)
+		var names []string
+		for _, s := range sources {
+			names = append(names, s.name)
+		}
+		assert.ElementsMatch(t, []string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "sub", "b.yaml")}, names)
+	})
+
+	t.Run("NoMatches_IsAnError", func(t *testing.T) {
+		_, err := resolveSources([]string{filepath.Join(dir, "*.missing")})
+		assert.Error(t, err)
+	})
+}
+
+func TestCLI_processSource_WriteUpdatesFileInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("b: 2\na: 1\n"), 0o644))
+
+	cli := CLI{YamlPaths: []string{"."}, Write: true}
+	diff, err := cli.processSource(source{name: path})
+	assert.NoError(t, err)
+	assert.False(t, diff)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "a: 1\nb: 2\n", string(data))
+}
+
+func TestCLI_processSource_CheckReportsUnsortedRegardlessOfOutputFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("b: 2\na: 1\n"), 0o644))
+
+	cli := CLI{YamlPaths: []string{"."}, Check: true, OutputFormat: "json"}
+	diff, err := cli.processSource(source{name: path})
+	assert.NoError(t, err)
+	assert.True(t, diff)
+
+	// --check never writes, regardless of --output.
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "b: 2\na: 1\n", string(data))
+}
+
+func TestCLI_processSource_CheckAlreadySortedWithOutputFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("a: 1\nb: 2\n"), 0o644))
+
+	// Even with --output json, --check must compare against the re-encoded
+	// YAML, not JSON, so an already-sorted YAML file still reports clean.
+	cli := CLI{YamlPaths: []string{"."}, Check: true, OutputFormat: "json"}
+	diff, err := cli.processSource(source{name: path})
+	assert.NoError(t, err)
+	assert.False(t, diff)
+}
+
+func TestUnifiedDiff_RendersChanges(t *testing.T) {
+	diff, err := unifiedDiff("example.yaml", []byte("name: John\nage: 30\n"), []byte("age: 30\nname: John\n"))
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "--- example.yaml")
+	assert.Contains(t, diff, "+++ example.yaml")
+	assert.Contains(t, diff, "+age: 30")
+	assert.Contains(t, diff, "-age: 30")
+}
+
+func TestUnifiedDiff_NoChangesIsEmpty(t *testing.T) {
+	diff, err := unifiedDiff("example.yaml", []byte("age: 30\n"), []byte("age: 30\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestCLI_encodeDocs_JSON(t *testing.T) {
+	tests := map[string]struct {
+		ndjson   bool
+		docs     []string
+		expected string
+	}{
+		"SingleDocument_PreservesSortedKeyOrderAndDecodesScalars": {
+			docs:     []string{"b: true\na: 1\nc: ~\nd: hello\n"},
+			expected: "{\n  \"b\": true,\n  \"a\": 1,\n  \"c\": null,\n  \"d\": \"hello\"\n}\n",
+		},
+		"Float_DecodesAsNumberNotString": {
+			docs:     []string{"price: 3.14\n"},
+			expected: "{\n  \"price\": 3.14\n}\n",
+		},
+		"MultipleDocuments_WrapInArray": {
+			docs:     []string{"a: 1\n", "b: 2\n"},
+			expected: "[\n  {\n    \"a\": 1\n  },\n  {\n    \"b\": 2\n  }\n]\n",
+		},
+		"Ndjson_OneCompactLinePerDocument": {
+			ndjson:   true,
+			docs:     []string{"b: 1\na: 2\n", "c: 3\n"},
+			expected: "{\"b\":1,\"a\":2}\n{\"c\":3}\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			docs := make([]*yaml.Node, len(tc.docs))
+			for i, s := range tc.docs {
+				var doc yaml.Node
+				assert.NoError(t, yaml.Unmarshal([]byte(s), &doc))
+				docs[i] = &doc
+			}
+
+			out, err := encodeJSON(docs, tc.ndjson)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+}
+
+func TestCLI_encodeDocs_UnknownFormat(t *testing.T) {
+	cli := CLI{OutputFormat: "xml"}
+	_, err := cli.encodeDocs(nil, 2)
+	assert.ErrorContains(t, err, "xml")
+}
+
+func TestCLI_SortYaml_SortKeySelector(t *testing.T) {
+	tests := map[string]struct {
+		paths    []string
+		content  string
+		expected string
+	}{
+		"ByNestedKey_SortsBySubPath": {
+			paths: []string{".rules[*by=metadata.name]"},
+			content: `
+rules:
+- metadata:
+    name: zeta
+- metadata:
+    name: alpha
+`,
+			expected: `
+rules:
+  - metadata:
+      name: alpha
+  - metadata:
+      name: zeta
+`,
+		},
+		"DescModifier_ReversesOrder": {
+			paths: []string{".containers[*by=name,desc]"},
+			content: `
+containers:
+- name: alpha
+- name: zeta
+- name: mid
+`,
+			expected: `
+containers:
+  - name: zeta
+  - name: mid
+  - name: alpha
+`,
+		},
+		"NumericModifier_SortsAsNumbers": {
+			paths: []string{".rules[*by=priority,numeric]"},
+			content: `
+rules:
+- priority: 10
+- priority: 2
+- priority: 1
+`,
+			expected: `
+rules:
+  - priority: 1
+  - priority: 2
+  - priority: 10
+`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			trim := func(s string) string { return strings.TrimPrefix(s, "\n") }
+			root := decodeYAML(t, trim(tt.content))
+			cli := CLI{YamlPaths: tt.paths, SortType: "alphanumeric"}
+
+			err := cli.SortYaml([]*yaml.Node{root})
+			assert.NoError(t, err)
+
+			output := encodeYAML(t, root)
+			assert.Equal(t, trim(tt.expected), output)
+		})
+	}
+}
+
+func TestCLI_Parse_CommaBearingFlagsAreNotSplit(t *testing.T) {
+	var cli CLI
+	parser, err := kong.New(&cli)
+	assert.NoError(t, err)
+
+	_, err = parser.Parse([]string{
+		"-f", "a.yaml",
+		"-p", ".rules[*by=priority,numeric]",
+	})
+	assert.NoError(t, err)
+
+	// Without sep:"none" on the yaml-path/file flags, kong's default []string
+	// parsing splits on ',' and turns one path into two bogus ones.
+	assert.Equal(t, []string{"a.yaml"}, cli.InputFiles)
+	assert.Equal(t, []string{".rules[*by=priority,numeric]"}, cli.YamlPaths)
+}
+
+func TestCLI_SortYaml_CustomPreset(t *testing.T) {
+	cli := CLI{
+		YamlPaths: []string{"."},
+		SortType:  "my-preset",
+		presets: map[string]SortPreset{
+			"my-preset": {
+				Keys:     []string{"kind", "name"},
+				Patterns: []string{"x-*"},
+			},
+		},
+	}
+	root := decodeYAML(t, strings.TrimPrefix(`
+name: demo
+x-extra: true
+kind: Widget
+age: 5
+`, "\n"))
+
+	err := cli.SortYaml([]*yaml.Node{root})
+	assert.NoError(t, err)
+
+	output := encodeYAML(t, root)
+	assert.Equal(t, strings.TrimPrefix(`
+kind: Widget
+name: demo
+x-extra: true
+age: 5
+`, "\n"), output)
+}
+
+func TestCLI_SortYaml_CustomPreset_PathsRestrictionWithDocSelector(t *testing.T) {
+	cli := CLI{
+		YamlPaths: []string{"#0.metadata"},
+		SortType:  "my-preset",
+		presets: map[string]SortPreset{
+			"my-preset": {
+				Keys:  []string{"name", "namespace"},
+				Paths: []string{"metadata"},
+			},
+		},
+	}
+	doc0 := decodeYAML(t, strings.TrimPrefix(`
+metadata:
+  namespace: default
+  name: demo
+`, "\n"))
+	doc1 := decodeYAML(t, strings.TrimPrefix(`
+metadata:
+  namespace: default
+  name: demo
+`, "\n"))
+
+	err := cli.SortYaml([]*yaml.Node{doc0, doc1})
+	assert.NoError(t, err)
+
+	// doc0 matches the preset's path restriction ("metadata") once the '#0.'
+	// document selector is stripped, so it gets sorted by the preset.
+	assert.Equal(t, strings.TrimPrefix(`
+metadata:
+  name: demo
+  namespace: default
+`, "\n"), encodeYAML(t, doc0))
+
+	// doc1 is untouched by this path (the selector pins it to document 0).
+	assert.Equal(t, strings.TrimPrefix(`
+metadata:
+  namespace: default
+  name: demo
+`, "\n"), encodeYAML(t, doc1))
+}
+
+func TestCLI_loadPresets_ConfigOverridesBuiltinHuman(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "presets.yaml")
+	config := `
+presets:
+  human:
+    keys: [kind, apiVersion]
+`
+	assert.NoError(t, os.WriteFile(configPath, []byte(strings.TrimPrefix(config, "\n")), 0o644))
+
+	cli := CLI{Config: configPath}
+	assert.NoError(t, cli.loadPresets())
+
+	preset, ok := cli.presetFor(humanPresetName)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"kind", "apiVersion"}, preset.Keys)
+}
+
+// decodeYAMLDocsForTest decodes every document in a multi-document YAML string.
+func decodeYAMLDocsForTest(t *testing.T, s string) []*yaml.Node {
+	t.Helper()
+	dec := yaml.NewDecoder(strings.NewReader(s))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to decode YAML: %v", err)
+		}
+		docs = append(docs, &doc)
+	}
+	return docs
+}
+
 // decodeYAML decodes a YAML document string into a *yaml.Node (DocumentNode)
 func decodeYAML(t *testing.T, s string) *yaml.Node {
 	t.Helper()